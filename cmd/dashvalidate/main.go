@@ -0,0 +1,64 @@
+// Command dashvalidate prints structured diagnostics for a DASH MPD
+// read from stdin, or fetched from one or more URLs given as arguments.
+package main
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+
+	"github.com/Cawb07/go-dash/mpd"
+	"github.com/Cawb07/go-dash/mpd/validate"
+)
+
+func main() {
+	args := os.Args[1:]
+	if len(args) == 0 {
+		os.Exit(run("(stdin)", os.Stdin))
+	}
+
+	status := 0
+	for _, arg := range args {
+		resp, err := http.Get(arg)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %v\n", arg, err)
+			status = 1
+			continue
+		}
+		if code := run(arg, resp.Body); code != 0 {
+			status = code
+		}
+		resp.Body.Close()
+	}
+	os.Exit(status)
+}
+
+func run(source string, r io.Reader) int {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s: %v\n", source, err)
+		return 1
+	}
+
+	m, err := mpd.Parse(data)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s: %v\n", source, err)
+		return 1
+	}
+
+	diags := validate.Validate(m)
+	if len(diags) == 0 {
+		fmt.Printf("%s: no issues found\n", source)
+		return 0
+	}
+
+	status := 0
+	for _, d := range diags {
+		fmt.Printf("%s: %s\n", source, d)
+		if d.Severity == validate.Error {
+			status = 1
+		}
+	}
+	return status
+}
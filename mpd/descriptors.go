@@ -0,0 +1,75 @@
+package mpd
+
+import "encoding/xml"
+
+// Descriptor is the common shape shared by DASH's generic descriptor
+// elements (Role, Accessibility, EssentialProperty,
+// SupplementalProperty), per ISO/IEC 23009-1 clause 5.8.2.
+type Descriptor struct {
+	SchemeIdUri *string    `xml:"schemeIdUri,attr"`
+	Value       *string    `xml:"value,attr,omitempty"`
+	ID          *string    `xml:"id,attr,omitempty"`
+	Unknown     []xml.Attr `xml:",any,attr"`
+}
+
+// Role identifies the role(s) an AdaptationSet plays, e.g.
+// schemeIdUri="urn:mpeg:dash:role:2011" value="main".
+type Role Descriptor
+
+// Accessibility identifies an accessibility scheme an AdaptationSet
+// conforms to, e.g. a hearing-impaired or visually-impaired rendering.
+type Accessibility Descriptor
+
+// EssentialProperty is metadata a client must understand, and can't
+// safely ignore, to process the element it's declared on correctly.
+type EssentialProperty Descriptor
+
+// SupplementalProperty is metadata that enhances processing but can be
+// safely ignored by a client that doesn't understand schemeIdUri.
+type SupplementalProperty Descriptor
+
+// InbandEventStream declares that the media segments carry 'emsg'
+// boxes for the given scheme, per ISO/IEC 23009-1 clause 5.10.3.
+type InbandEventStream Descriptor
+
+// Label is a human-readable name for the AdaptationSet or
+// Representation it's declared on.
+type Label struct {
+	ID      *string    `xml:"id,attr,omitempty"`
+	Lang    *string    `xml:"lang,attr,omitempty"`
+	Value   string     `xml:",chardata"`
+	Unknown []xml.Attr `xml:",any,attr"`
+}
+
+// ContentProtection describes a DRM system protecting the content, per
+// ISO/IEC 23009-1 clause 5.8.4.1. DefaultKID/PSSH/PRO carry the
+// cenc:default_KID, cenc:pssh and mspr:pro extensions commonly found in
+// CENC and PlayReady manifests.
+type ContentProtection struct {
+	SchemeIdUri *string    `xml:"schemeIdUri,attr"`
+	Value       *string    `xml:"value,attr,omitempty"`
+	DefaultKID  *string    `xml:"urn:mpeg:cenc:2013 default_KID,attr,omitempty"`
+	PSSH        *string    `xml:"urn:mpeg:cenc:2013 pssh,omitempty"`
+	PRO         *string    `xml:"urn:microsoft:playready pro,omitempty"`
+	Unknown     []xml.Attr `xml:",any,attr"`
+}
+
+// EventStream carries a timed sequence of application-defined Events
+// within a Period, per ISO/IEC 23009-1 clause 5.10.2.
+type EventStream struct {
+	SchemeIdUri *string    `xml:"schemeIdUri,attr"`
+	Value       *string    `xml:"value,attr,omitempty"`
+	Timescale   *uint32    `xml:"timescale,attr,omitempty"`
+	Events      []*Event   `xml:"Event,omitempty"`
+	Unknown     []xml.Attr `xml:",any,attr"`
+}
+
+// Event is one entry of an EventStream. Its payload is scheme-specific
+// and so is kept as raw, unparsed XML.
+type Event struct {
+	PresentationTime *uint64    `xml:"presentationTime,attr,omitempty"`
+	Duration         *uint64    `xml:"duration,attr,omitempty"`
+	ID               *uint32    `xml:"id,attr,omitempty"`
+	Content          string     `xml:",innerxml"`
+	Unknown          []xml.Attr `xml:",any,attr"`
+}
@@ -6,7 +6,7 @@ import (
 	"encoding/xml"
 	"errors"
 	"fmt"
-	"regexp"
+	"math"
 	"strconv"
 	"strings"
 	"time"
@@ -21,20 +21,17 @@ const (
 	Second               = 1000 * Millisecond
 	Minute               = 60 * Second
 	Hour                 = 60 * Minute
-)
 
-var (
-	rStart   = "^P"          // Must start with a 'P'
-	rDays    = "(\\d+D)?"    // We only allow Days for durations, not Months or Years
-	rTime    = "(?:T"        // If there's any 'time' units then they must be preceded by a 'T'
-	rHours   = "(\\d+H)?"    // Hours
-	rMinutes = "(\\d+M)?"    // Minutes
-	rSeconds = "([\\d.]+S)?" // Seconds (Potentially decimal)
-	rEnd     = ")?$"         // end of regex must close "T" capture group
+	// Nominal, non-calendar-exact conversions used when parsing and
+	// formatting the Y/M/W components of an xs:duration. A year is taken
+	// to be 365 days, a month 30 days, and a week 7 days, as recommended
+	// by ISO 8601 for durations that aren't anchored to a calendar date.
+	day   = 24 * Hour
+	week  = 7 * day
+	month = 30 * day
+	year  = 365 * day
 )
 
-var xmlDurationRegex = regexp.MustCompile(rStart + rDays + rTime + rHours + rMinutes + rSeconds + rEnd)
-
 // Nanoseconds returns the duration as an integer nanosecond count.
 func (d Duration) Nanoseconds() int64 { return int64(d) }
 
@@ -90,77 +87,72 @@ func (d *Duration) UnmarshalXMLAttr(attr xml.Attr) error {
 	return nil
 }
 
-// String renders a Duration in XML Duration Data Type format
+// String renders a Duration in XML Duration Data Type format, e.g.
+// "PT1H30M0S", "P1Y2M10DT2H30M0S" or "-PT5S". The Y/M/D/H/M components
+// are only emitted when non-zero; whenever a time part is written it
+// always ends with a seconds component, even "0S". Y/M/D use the same
+// nominal (year=365d, month=30d)
+// conversion as parseDuration so round-tripping a value produced by
+// String always reproduces the same Duration.
 func (d *Duration) String() string {
-	// Largest time is 2540400h10m10.000000000s
-	var buf [32]byte
-	w := len(buf)
-
 	u := uint64(*d)
 	neg := *d < 0
 	if neg {
 		u = -u
 	}
 
-	if u < uint64(time.Second) {
-		// Special case: if duration is smaller than a second,
-		// use smaller units, like 1.2ms
-		var prec int
-		w--
-		buf[w] = 'S'
-		w--
-		if u == 0 {
-			return "PT0S"
-		}
-		/*
-			switch {
-			case u < uint64(Millisecond):
-				// print microseconds
-				prec = 3
-				// U+00B5 'µ' micro sign == 0xC2 0xB5
-				w-- // Need room for two bytes.
-				copy(buf[w:], "µ")
-			default:
-				// print milliseconds
-				prec = 6
-				buf[w] = 'm'
-			}
-		*/
-		w, u = fmtFrac(buf[:w], u, prec)
-		w = fmtInt(buf[:w], u)
-	} else {
-		w--
-		buf[w] = 'S'
-
-		w, u = fmtFrac(buf[:w], u, 9)
-
-		// u is now integer seconds
-		w = fmtInt(buf[:w], u%60)
-		u /= 60
-
-		// u is now integer minutes
-		if u > 0 {
-			w--
-			buf[w] = 'M'
-			w = fmtInt(buf[:w], u%60)
-			u /= 60
-
-			// u is now integer hours
-			// Stop at hours because days can be different lengths.
-			if u > 0 {
-				w--
-				buf[w] = 'H'
-				w = fmtInt(buf[:w], u)
-			}
-		}
+	if u == 0 {
+		return "PT0S"
 	}
 
+	years := u / uint64(year)
+	u %= uint64(year)
+	months := u / uint64(month)
+	u %= uint64(month)
+	days := u / uint64(day)
+	u %= uint64(day)
+	hours := u / uint64(Hour)
+	u %= uint64(Hour)
+	minutes := u / uint64(Minute)
+	u %= uint64(Minute)
+
+	// u is now sub-minute nanoseconds; split it into whole seconds and a
+	// fractional remainder using the same backward-fill trick as the
+	// stdlib time package.
+	var secBuf [32]byte
+	w := len(secBuf)
+	w--
+	secBuf[w] = 'S'
+	w, u = fmtFrac(secBuf[:w], u, 9)
+	w = fmtInt(secBuf[:w], u)
+	secs := string(secBuf[w:])
+
+	var b strings.Builder
 	if neg {
-		w--
-		buf[w] = '-'
+		b.WriteByte('-')
+	}
+	b.WriteByte('P')
+	if years > 0 {
+		fmt.Fprintf(&b, "%dY", years)
+	}
+	if months > 0 {
+		fmt.Fprintf(&b, "%dM", months)
+	}
+	if days > 0 {
+		fmt.Fprintf(&b, "%dD", days)
+	}
+	if hours > 0 || minutes > 0 || secs != "0S" {
+		b.WriteByte('T')
+		if hours > 0 {
+			fmt.Fprintf(&b, "%dH", hours)
+		}
+		if minutes > 0 {
+			fmt.Fprintf(&b, "%dM", minutes)
+		}
+		b.WriteString(secs)
 	}
 
-	return "PT" + string(buf[w:])
+	return b.String()
 }
 
 // fmtFrac formats the fraction of v/10**prec (e.g., ".12345") into the
@@ -204,54 +196,162 @@ func fmtInt(buf []byte, v uint64) int {
 	return w
 }
 
+// parseDuration parses an xs:duration string using the nominal (calendar
+// unsafe) conversions documented on Duration.String: Y=365d, M=30d, W=7d.
+// It is the permissive mode used by UnmarshalXMLAttr. Callers that need
+// calendar-safe arithmetic and want nominal components rejected should use
+// ParseDurationStrict instead.
 func parseDuration(str string) (time.Duration, error) {
+	return parseISODuration(str, false)
+}
+
+// ParseDurationStrict parses an xs:duration string the same way
+// parseDuration does, but rejects the Y (year), M (month) and W (week)
+// components, since they're nominal approximations rather than exact
+// units of time. Only D, H, M (minute) and S remain, all of which map to
+// a fixed, calendar-independent number of nanoseconds.
+func ParseDurationStrict(str string) (time.Duration, error) {
+	return parseISODuration(str, true)
+}
+
+func parseISODuration(str string, strict bool) (time.Duration, error) {
+	orig := str
 	if len(str) < 3 {
-		return 0, errors.New("At least one number and designator are required")
+		return 0, fmt.Errorf("mpd: invalid duration %q: at least one number and designator are required", orig)
 	}
 
-	if strings.Contains(str, "-") {
-		return 0, errors.New("Duration cannot be negative")
+	neg := false
+	if strings.HasPrefix(str, "-") {
+		neg = true
+		str = str[1:]
 	}
 
-	// Check that only the parts we expect exist and that everything's in the correct order
-	if !xmlDurationRegex.Match([]byte(str)) {
-		return 0, errors.New("Duration must be in the format: P[nD][T[nH][nM][nS]]")
+	if len(str) == 0 || str[0] != 'P' {
+		return 0, fmt.Errorf("mpd: invalid duration %q: must start with P", orig)
+	}
+	str = str[1:]
+	if str == "" {
+		return 0, fmt.Errorf("mpd: invalid duration %q: at least one component is required", orig)
 	}
 
-	var parts = xmlDurationRegex.FindStringSubmatch(str)
-	var total time.Duration
-
-	if parts[1] != "" {
-		days, err := strconv.Atoi(strings.TrimRight(parts[1], "D"))
-		if err != nil {
-			return 0, fmt.Errorf("Error parsing Days: %s", err)
+	datePart := str
+	timePart := ""
+	hasTime := false
+	if idx := strings.IndexByte(str, 'T'); idx >= 0 {
+		datePart = str[:idx]
+		timePart = str[idx+1:]
+		hasTime = true
+		if timePart == "" {
+			return 0, fmt.Errorf("mpd: invalid duration %q: T designator with no time components following it", orig)
 		}
-		total += time.Duration(days) * time.Hour * 24
 	}
 
-	if parts[2] != "" {
-		hours, err := strconv.Atoi(strings.TrimRight(parts[2], "H"))
-		if err != nil {
-			return 0, fmt.Errorf("Error parsing Hours: %s", err)
+	// Accumulated as float64, not time.Duration, so a too-large duration
+	// can be detected and reported instead of silently overflowing
+	// time.Duration's int64 nanosecond range on the final conversion.
+	var total float64
+
+	if datePart != "" {
+		if strings.IndexByte(datePart, 'W') >= 0 {
+			// The week form (PnW) is an alternative to, and cannot be
+			// combined with, the Y/M/D and T... forms.
+			if hasTime || !strings.HasSuffix(datePart, "W") {
+				return 0, fmt.Errorf("mpd: invalid duration %q: W cannot be combined with other components", orig)
+			}
+			if strict {
+				return 0, fmt.Errorf("mpd: invalid duration %q: week components are not permitted in strict mode", orig)
+			}
+			val, rem, err := leadingNumber(strings.TrimSuffix(datePart, "W"))
+			if err != nil || rem != "" {
+				return 0, fmt.Errorf("mpd: invalid duration %q: bad week component", orig)
+			}
+			total += val * float64(week)
+		} else {
+			values, err := parseDesignatedComponents(datePart, "YMD")
+			if err != nil {
+				return 0, fmt.Errorf("mpd: invalid duration %q: %w", orig, err)
+			}
+			if strict {
+				if _, ok := values['Y']; ok {
+					return 0, fmt.Errorf("mpd: invalid duration %q: year components are not permitted in strict mode", orig)
+				}
+				if _, ok := values['M']; ok {
+					return 0, fmt.Errorf("mpd: invalid duration %q: month components are not permitted in strict mode", orig)
+				}
+			}
+			total += values['Y'] * float64(year)
+			total += values['M'] * float64(month)
+			total += values['D'] * float64(day)
 		}
-		total += time.Duration(hours) * time.Hour
 	}
 
-	if parts[3] != "" {
-		mins, err := strconv.Atoi(strings.TrimRight(parts[3], "M"))
+	if timePart != "" {
+		values, err := parseDesignatedComponents(timePart, "HMS")
 		if err != nil {
-			return 0, fmt.Errorf("Error parsing Minutes: %s", err)
+			return 0, fmt.Errorf("mpd: invalid duration %q: %w", orig, err)
 		}
-		total += time.Duration(mins) * time.Minute
+		total += values['H'] * float64(time.Hour)
+		total += values['M'] * float64(time.Minute)
+		total += values['S'] * float64(time.Second)
+	}
+
+	if neg {
+		total = -total
 	}
 
-	if parts[4] != "" {
-		secs, err := strconv.ParseFloat(strings.TrimRight(parts[4], "S"), 64)
+	if total > math.MaxInt64 || total < math.MinInt64 {
+		return 0, fmt.Errorf("mpd: invalid duration %q: magnitude overflows time.Duration's range", orig)
+	}
+
+	return time.Duration(total), nil
+}
+
+// parseDesignatedComponents scans a sequence of <number><designator> pairs
+// (e.g. "1Y2M10D" against designators "YMD") and requires that designators
+// appear in the given order with no repeats, which is what the ISO 8601
+// duration grammar mandates. It returns the numeric value keyed by
+// designator byte; any designator not present in the input is absent
+// (and so defaults to 0 in the caller).
+func parseDesignatedComponents(s, designators string) (map[byte]float64, error) {
+	values := make(map[byte]float64)
+	pos := 0
+	for len(s) > 0 {
+		val, rem, err := leadingNumber(s)
 		if err != nil {
-			return 0, fmt.Errorf("Error parsing Seconds: %s", err)
+			return nil, err
+		}
+		if rem == "" {
+			return nil, fmt.Errorf("missing designator after %v", val)
+		}
+		d := rem[0]
+		idx := strings.IndexByte(designators[pos:], d)
+		if idx < 0 {
+			return nil, fmt.Errorf("unexpected or out-of-order designator %q", d)
 		}
-		total += time.Duration(secs * float64(time.Second))
+		if _, dup := values[d]; dup {
+			return nil, fmt.Errorf("duplicate designator %q", d)
+		}
+		pos += idx + 1
+		values[d] = val
+		s = rem[1:]
 	}
+	return values, nil
+}
 
-	return total, nil
+// leadingNumber consumes a leading decimal number (with an optional
+// fractional part, e.g. "0.5" or "10") from s and returns its value along
+// with the unconsumed remainder.
+func leadingNumber(s string) (val float64, rem string, err error) {
+	i := 0
+	for i < len(s) && (s[i] == '.' || (s[i] >= '0' && s[i] <= '9')) {
+		i++
+	}
+	if i == 0 {
+		return 0, s, errors.New("expected a number")
+	}
+	val, err = strconv.ParseFloat(s[:i], 64)
+	if err != nil {
+		return 0, s, fmt.Errorf("invalid number %q: %w", s[:i], err)
+	}
+	return val, s[i:], nil
 }
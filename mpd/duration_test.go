@@ -0,0 +1,145 @@
+package mpd
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseDuration(t *testing.T) {
+	cases := []struct {
+		in   string
+		want time.Duration
+	}{
+		{"PT0.5H", 30 * time.Minute},
+		{"P1Y2M10DT2H30M", time.Duration(year) + 2*time.Duration(month) + 10*time.Duration(day) + 2*time.Hour + 30*time.Minute},
+		{"P4W", 4 * time.Duration(week)},
+		{"-PT5S", -5 * time.Second},
+		{"PT0S", 0},
+		{"PT1H", time.Hour},
+	}
+
+	for _, c := range cases {
+		t.Run(c.in, func(t *testing.T) {
+			got, err := parseDuration(c.in)
+			if err != nil {
+				t.Fatalf("parseDuration(%q) returned error: %v", c.in, err)
+			}
+			if got != c.want {
+				t.Errorf("parseDuration(%q) = %v, want %v", c.in, got, c.want)
+			}
+		})
+	}
+}
+
+func TestDurationStringRoundTrip(t *testing.T) {
+	cases := []string{
+		"PT0.5H",
+		"P1Y2M10DT2H30M",
+		"-PT5S",
+		"PT1H",
+		"PT0S",
+	}
+
+	for _, in := range cases {
+		t.Run(in, func(t *testing.T) {
+			parsed, err := parseDuration(in)
+			if err != nil {
+				t.Fatalf("parseDuration(%q) returned error: %v", in, err)
+			}
+			d := Duration(parsed)
+			str := d.String()
+
+			reparsed, err := parseDuration(str)
+			if err != nil {
+				t.Fatalf("parseDuration(d.String()) = parseDuration(%q) returned error: %v", str, err)
+			}
+			if reparsed != parsed {
+				t.Errorf("round trip through String() changed the value: %v -> %q -> %v", parsed, str, reparsed)
+			}
+		})
+	}
+}
+
+func TestDurationString(t *testing.T) {
+	cases := []struct {
+		in   Duration
+		want string
+	}{
+		{0, "PT0S"},
+		{Duration(30 * time.Minute), "PT30M0S"},
+		{Duration(time.Duration(year) + 2*time.Duration(month) + 10*time.Duration(day) + 2*time.Hour + 30*time.Minute), "P1Y2M10DT2H30M0S"},
+		{Duration(4 * time.Duration(week)), "P28D"},
+		{Duration(-5 * time.Second), "-PT5S"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.want, func(t *testing.T) {
+			if got := c.in.String(); got != c.want {
+				t.Errorf("Duration(%d).String() = %q, want %q", int64(c.in), got, c.want)
+			}
+		})
+	}
+}
+
+func TestParseDurationStrictRejectsNominalComponents(t *testing.T) {
+	cases := []string{"P1Y", "P1M", "P4W", "P1YT1H"}
+	for _, in := range cases {
+		t.Run(in, func(t *testing.T) {
+			if _, err := ParseDurationStrict(in); err == nil {
+				t.Errorf("ParseDurationStrict(%q) = nil error, want a rejection", in)
+			}
+		})
+	}
+}
+
+func TestParseDurationStrictAcceptsExactComponents(t *testing.T) {
+	got, err := ParseDurationStrict("P1DT2H30M")
+	if err != nil {
+		t.Fatalf("ParseDurationStrict returned error: %v", err)
+	}
+	want := time.Duration(day) + 2*time.Hour + 30*time.Minute
+	if got != want {
+		t.Errorf("ParseDurationStrict(\"P1DT2H30M\") = %v, want %v", got, want)
+	}
+}
+
+func TestParseDurationOverflow(t *testing.T) {
+	cases := []string{"P293Y", "P1000Y", "-P1000Y"}
+
+	for _, in := range cases {
+		t.Run(in, func(t *testing.T) {
+			got, err := parseDuration(in)
+			if err == nil {
+				t.Fatalf("parseDuration(%q) = %v, nil error, want an overflow error", in, got)
+			}
+			if !strings.Contains(err.Error(), "overflow") {
+				t.Errorf("parseDuration(%q) error = %q, want it to mention overflow", in, err.Error())
+			}
+		})
+	}
+}
+
+func TestParseDurationMalformed(t *testing.T) {
+	cases := []string{
+		"",
+		"PT",
+		"1H",
+		"P",
+		"PT1HT2M",
+		"P1W2D",
+		"PT1X",
+		"P1D2D",
+		"PT1S1S",
+	}
+
+	for _, in := range cases {
+		t.Run(in, func(t *testing.T) {
+			if _, err := parseDuration(in); err == nil {
+				t.Errorf("parseDuration(%q) = nil error, want an error", in)
+			} else if !strings.Contains(err.Error(), "invalid duration") {
+				t.Errorf("parseDuration(%q) error = %q, want it to mention the invalid duration", in, err.Error())
+			}
+		})
+	}
+}
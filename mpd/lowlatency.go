@@ -0,0 +1,139 @@
+package mpd
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// dashLowLatencyProfile is the DASH-IF low-latency extension profile
+// that, together with the base isoff-live profile, identifies a
+// CMAF low-latency manifest.
+const (
+	dashIsoffLiveProfile    = "urn:mpeg:dash:profile:isoff-live:2011"
+	dashLowLatencyProfile   = "http://www.dashif.org/guidelines/low-latency-live-v5"
+	lowLatencyPlaybackSpeed = 0.04 // +/-4%, the DASH-IF guidelines' worked example
+)
+
+// ProducerReferenceTime anchors a Representation's presentation
+// timeline to wall-clock time, per the DASH-IF low-latency guidelines.
+// It can be declared inband (carried in 'prft' boxes within the
+// segments themselves) or only in the manifest.
+type ProducerReferenceTime struct {
+	ID               *uint32    `xml:"id,attr,omitempty"`
+	Inband           *bool      `xml:"inband,attr,omitempty"`
+	Type             *string    `xml:"type,attr,omitempty"` // encoder|captured|application
+	WallClockTime    *string    `xml:"wallClockTime,attr,omitempty"`
+	PresentationTime *uint64    `xml:"presentationTime,attr,omitempty"`
+	UTCTiming        *UTCTiming `xml:"UTCTiming,omitempty"`
+	Unknown          []xml.Attr `xml:",any,attr"`
+}
+
+// UTCTiming identifies a method (and its parameters) a client can use
+// to synchronize its clock with the one ProducerReferenceTime values
+// are expressed against, per ISO/IEC 23009-1 clause 5.8.4.11.
+type UTCTiming struct {
+	SchemeIdUri *string    `xml:"schemeIdUri,attr,omitempty"`
+	Value       *string    `xml:"value,attr,omitempty"`
+	Unknown     []xml.Attr `xml:",any,attr"`
+}
+
+// ServiceDescription groups latency and playback-rate targets for a
+// service, per the DASH-IF low-latency guidelines. It may appear under
+// MPD or Period.
+type ServiceDescription struct {
+	ID           *uint32       `xml:"id,attr,omitempty"`
+	Latency      *Latency      `xml:"Latency,omitempty"`
+	PlaybackRate *PlaybackRate `xml:"PlaybackRate,omitempty"`
+	Unknown      []xml.Attr    `xml:",any,attr"`
+}
+
+// Latency expresses the target and acceptable range, in milliseconds,
+// for the delay between a sample being captured and presented.
+type Latency struct {
+	Target  *uint32    `xml:"target,attr,omitempty"`
+	Max     *uint32    `xml:"max,attr,omitempty"`
+	Min     *uint32    `xml:"min,attr,omitempty"`
+	Unknown []xml.Attr `xml:",any,attr"`
+}
+
+// PlaybackRate is the range a player may speed up or slow down playback
+// within to catch up to, or fall back to, the target latency.
+type PlaybackRate struct {
+	Max     *float32   `xml:"max,attr,omitempty"`
+	Min     *float32   `xml:"min,attr,omitempty"`
+	Unknown []xml.Attr `xml:",any,attr"`
+}
+
+// SetLowLatency configures the AdaptationSet's SegmentTemplate for CMAF
+// chunked transfer: it sets @availabilityTimeOffset to the portion of
+// each segment that's available before the whole segment is encoded
+// (segmentDuration - chunkDuration) and @availabilityTimeComplete to
+// false, then records targetLatency as a ServiceDescription/Latency on
+// the enclosing Period. It returns an error if the AdaptationSet itself
+// doesn't already have a SegmentTemplate with @duration and @timescale
+// set (a SegmentTemplate inherited from the Period isn't used), since
+// those are needed to compute the segment
+// duration, or if the AdaptationSet isn't attached to a Period, since
+// there's nowhere to record the ServiceDescription.
+func (a *AdaptationSet) SetLowLatency(chunkDuration, targetLatency time.Duration) error {
+	tmpl := a.SegmentTemplate
+	if tmpl == nil {
+		return errors.New("mpd: AdaptationSet has no SegmentTemplate to make low-latency")
+	}
+	if tmpl.Duration == nil || tmpl.Timescale == nil {
+		return errors.New("mpd: SegmentTemplate needs @duration and @timescale set before SetLowLatency")
+	}
+	if a.period == nil {
+		return errors.New("mpd: AdaptationSet isn't attached to a Period, nowhere to record the ServiceDescription")
+	}
+
+	segmentDuration := time.Duration(float64(*tmpl.Duration) / float64(*tmpl.Timescale) * float64(time.Second))
+	offset := segmentDuration - chunkDuration
+	if offset < 0 {
+		return fmt.Errorf("mpd: chunkDuration %s is longer than the segment duration %s", chunkDuration, segmentDuration)
+	}
+
+	availabilityTimeOffset := float32(offset.Seconds())
+	tmpl.AvailabilityTimeOffset = &availabilityTimeOffset
+	availabilityTimeComplete := false
+	tmpl.AvailabilityTimeComplete = &availabilityTimeComplete
+
+	target := uint32(targetLatency.Milliseconds())
+	min := uint32(float64(target) * (1 - lowLatencyPlaybackSpeed))
+	max := uint32(float64(target) * (1 + lowLatencyPlaybackSpeed))
+	playbackMin := float32(1 - lowLatencyPlaybackSpeed)
+	playbackMax := float32(1 + lowLatencyPlaybackSpeed)
+
+	sd := &ServiceDescription{
+		Latency:      &Latency{Target: &target, Min: &min, Max: &max},
+		PlaybackRate: &PlaybackRate{Min: &playbackMin, Max: &playbackMax},
+	}
+	a.period.ServiceDescriptions = append(a.period.ServiceDescriptions, sd)
+
+	return nil
+}
+
+// SetLowLatencyProfile adds the DASH-IF low-latency extension profile
+// to @profiles, alongside the isoff-live base profile it extends,
+// without duplicating either if already present.
+func (m *MPD) SetLowLatencyProfile() {
+	m.addProfile(dashIsoffLiveProfile)
+	m.addProfile(dashLowLatencyProfile)
+}
+
+func (m *MPD) addProfile(profile string) {
+	if m.Profiles == nil || *m.Profiles == "" {
+		m.Profiles = &profile
+		return
+	}
+	for _, p := range strings.Split(*m.Profiles, ",") {
+		if p == profile {
+			return
+		}
+	}
+	combined := *m.Profiles + "," + profile
+	m.Profiles = &combined
+}
@@ -0,0 +1,117 @@
+package mpd
+
+import (
+	"testing"
+	"time"
+)
+
+func newTestAdaptationSetWithTemplate() *AdaptationSet {
+	m := NewMPD(dashIsoffLiveProfile)
+	p := m.AddNewPeriod()
+	a := p.AddNewAdaptationSet()
+	timescale := int32(1000)
+	duration := int32(2000)
+	a.SegmentTemplate = &SegmentTemplate{
+		MultipleSegmentBase: MultipleSegmentBase{
+			SegmentBase: SegmentBase{Timescale: &timescale},
+			Duration:    &duration,
+		},
+	}
+	return a
+}
+
+func TestSetLowLatency(t *testing.T) {
+	a := newTestAdaptationSetWithTemplate()
+
+	if err := a.SetLowLatency(500*time.Millisecond, 3*time.Second); err != nil {
+		t.Fatalf("SetLowLatency returned error: %v", err)
+	}
+
+	tmpl := a.SegmentTemplate
+	if tmpl.AvailabilityTimeOffset == nil || *tmpl.AvailabilityTimeOffset != 1.5 {
+		t.Errorf("AvailabilityTimeOffset = %v, want 1.5", tmpl.AvailabilityTimeOffset)
+	}
+	if tmpl.AvailabilityTimeComplete == nil || *tmpl.AvailabilityTimeComplete != false {
+		t.Errorf("AvailabilityTimeComplete = %v, want false", tmpl.AvailabilityTimeComplete)
+	}
+
+	if len(a.period.ServiceDescriptions) != 1 {
+		t.Fatalf("Period.ServiceDescriptions has %d entries, want 1", len(a.period.ServiceDescriptions))
+	}
+	sd := a.period.ServiceDescriptions[0]
+	if sd.Latency == nil || sd.Latency.Target == nil || *sd.Latency.Target != 3000 {
+		t.Errorf("Latency.Target = %v, want 3000", sd.Latency)
+	}
+	if sd.PlaybackRate == nil || sd.PlaybackRate.Min == nil || sd.PlaybackRate.Max == nil {
+		t.Fatalf("PlaybackRate not set: %+v", sd.PlaybackRate)
+	}
+}
+
+func TestSetLowLatencyChunkLongerThanSegment(t *testing.T) {
+	a := newTestAdaptationSetWithTemplate()
+
+	err := a.SetLowLatency(3*time.Second, 1*time.Second)
+	if err == nil {
+		t.Fatal("expected an error when chunkDuration exceeds the segment duration")
+	}
+
+	tmpl := a.SegmentTemplate
+	if tmpl.AvailabilityTimeOffset != nil || tmpl.AvailabilityTimeComplete != nil {
+		t.Error("SegmentTemplate was mutated despite the error")
+	}
+	if len(a.period.ServiceDescriptions) != 0 {
+		t.Error("ServiceDescription was recorded despite the error")
+	}
+}
+
+func TestSetLowLatencyNoSegmentTemplate(t *testing.T) {
+	m := NewMPD(dashIsoffLiveProfile)
+	a := m.AddNewPeriod().AddNewAdaptationSet()
+
+	if err := a.SetLowLatency(500*time.Millisecond, 3*time.Second); err == nil {
+		t.Fatal("expected an error when the AdaptationSet has no SegmentTemplate")
+	}
+}
+
+func TestSetLowLatencyMissingDurationOrTimescale(t *testing.T) {
+	m := NewMPD(dashIsoffLiveProfile)
+	a := m.AddNewPeriod().AddNewAdaptationSet()
+	a.SegmentTemplate = &SegmentTemplate{}
+
+	if err := a.SetLowLatency(500*time.Millisecond, 3*time.Second); err == nil {
+		t.Fatal("expected an error when @duration/@timescale aren't set")
+	}
+}
+
+func TestSetLowLatencyNoPeriod(t *testing.T) {
+	a := &AdaptationSet{}
+	timescale := int32(1000)
+	duration := int32(2000)
+	a.SegmentTemplate = &SegmentTemplate{
+		MultipleSegmentBase: MultipleSegmentBase{
+			SegmentBase: SegmentBase{Timescale: &timescale},
+			Duration:    &duration,
+		},
+	}
+
+	err := a.SetLowLatency(500*time.Millisecond, 3*time.Second)
+	if err == nil {
+		t.Fatal("expected an error when the AdaptationSet isn't attached to a Period")
+	}
+
+	tmpl := a.SegmentTemplate
+	if tmpl.AvailabilityTimeOffset != nil || tmpl.AvailabilityTimeComplete != nil {
+		t.Error("SegmentTemplate was mutated despite the error")
+	}
+}
+
+func TestSetLowLatencyProfile(t *testing.T) {
+	m := NewMPD(dashIsoffLiveProfile)
+	m.SetLowLatencyProfile()
+	m.SetLowLatencyProfile()
+
+	want := dashIsoffLiveProfile + "," + dashLowLatencyProfile
+	if m.Profiles == nil || *m.Profiles != want {
+		t.Errorf("Profiles = %v, want %q (and calling it twice shouldn't duplicate either profile)", m.Profiles, want)
+	}
+}
@@ -0,0 +1,131 @@
+package mpd
+
+import "encoding/xml"
+
+// MPD is the root element of a DASH Media Presentation Description, as
+// defined by ISO/IEC 23009-1 clause 5.3.1.
+type MPD struct {
+	XMLName                    xml.Name              `xml:"MPD"`
+	XMLNs                      *string               `xml:"xmlns,attr,omitempty"`
+	Profiles                   *string               `xml:"profiles,attr,omitempty"`
+	Type                       *string               `xml:"type,attr,omitempty"`
+	MediaPresentationDuration  *Duration             `xml:"mediaPresentationDuration,attr,omitempty"`
+	MinBufferTime              *Duration             `xml:"minBufferTime,attr,omitempty"`
+	AvailabilityStartTime      *string               `xml:"availabilityStartTime,attr,omitempty"`
+	MinimumUpdatePeriod        *Duration             `xml:"minimumUpdatePeriod,attr,omitempty"`
+	SuggestedPresentationDelay *Duration             `xml:"suggestedPresentationDelay,attr,omitempty"`
+	BaseURL                    []*BaseURL            `xml:"BaseURL,omitempty"`
+	Periods                    []*Period             `xml:"Period,omitempty"`
+	ServiceDescriptions        []*ServiceDescription `xml:"ServiceDescription,omitempty"`
+	UTCTimings                 []*UTCTiming          `xml:"UTCTiming,omitempty"`
+	Unknown                    []xml.Attr            `xml:",any,attr"`
+}
+
+// NewMPD creates an MPD advertising the given DASH profile(s), e.g.
+// "urn:mpeg:dash:profile:isoff-live:2011".
+func NewMPD(profiles string) *MPD {
+	return &MPD{Profiles: &profiles}
+}
+
+// AddNewPeriod appends a new, empty Period to the MPD and returns it.
+func (m *MPD) AddNewPeriod() *Period {
+	p := &Period{mpd: m}
+	m.Periods = append(m.Periods, p)
+	return p
+}
+
+// BaseURL is a relative or absolute URI used to resolve the media and
+// segment URLs beneath the element it's declared on, per ISO/IEC
+// 23009-1 clause 5.6. It can appear on MPD, Period, AdaptationSet and
+// Representation; Representation.ResolveSegment walks that chain,
+// resolving each level's first BaseURL against the one above it.
+type BaseURL struct {
+	Value   *string    `xml:",chardata"`
+	Unknown []xml.Attr `xml:",any,attr"`
+}
+
+// Period is a Media Presentation interval, per ISO/IEC 23009-1 clause
+// 5.3.2. SegmentTemplate set here is inherited by every AdaptationSet
+// and Representation within the Period that doesn't declare its own.
+type Period struct {
+	ID                  *string               `xml:"id,attr,omitempty"`
+	Start               *Duration             `xml:"start,attr,omitempty"`
+	Duration            *Duration             `xml:"duration,attr,omitempty"`
+	BaseURL             []*BaseURL            `xml:"BaseURL,omitempty"`
+	SegmentTemplate     *SegmentTemplate      `xml:"SegmentTemplate,omitempty"`
+	AdaptationSets      []*AdaptationSet      `xml:"AdaptationSet,omitempty"`
+	ServiceDescriptions []*ServiceDescription `xml:"ServiceDescription,omitempty"`
+	EventStreams        []*EventStream        `xml:"EventStream,omitempty"`
+	Unknown             []xml.Attr            `xml:",any,attr"`
+
+	mpd *MPD
+}
+
+// AddNewAdaptationSet appends a new, empty AdaptationSet to the Period
+// and returns it.
+func (p *Period) AddNewAdaptationSet() *AdaptationSet {
+	a := &AdaptationSet{period: p}
+	p.AdaptationSets = append(p.AdaptationSets, a)
+	return a
+}
+
+// AdaptationSet groups Representations that are alternatives for the
+// same content, per ISO/IEC 23009-1 clause 5.3.3. SegmentTemplate set
+// here is inherited by every Representation within it that doesn't
+// declare its own.
+type AdaptationSet struct {
+	ID                     *uint32                  `xml:"id,attr,omitempty"`
+	MimeType               *string                  `xml:"mimeType,attr,omitempty"`
+	BaseURL                []*BaseURL               `xml:"BaseURL,omitempty"`
+	SegmentTemplate        *SegmentTemplate         `xml:"SegmentTemplate,omitempty"`
+	SegmentList            *SegmentList             `xml:"SegmentList,omitempty"`
+	Representations        []*Representation        `xml:"Representation,omitempty"`
+	ProducerReferenceTimes []*ProducerReferenceTime `xml:"ProducerReferenceTime,omitempty"`
+	Roles                  []*Role                  `xml:"Role,omitempty"`
+	Accessibilities        []*Accessibility         `xml:"Accessibility,omitempty"`
+	EssentialProperties    []*EssentialProperty     `xml:"EssentialProperty,omitempty"`
+	SupplementalProperties []*SupplementalProperty  `xml:"SupplementalProperty,omitempty"`
+	Labels                 []*Label                 `xml:"Label,omitempty"`
+	ContentProtections     []*ContentProtection     `xml:"ContentProtection,omitempty"`
+	InbandEventStreams     []*InbandEventStream     `xml:"InbandEventStream,omitempty"`
+	Unknown                []xml.Attr               `xml:",any,attr"`
+
+	period *Period
+}
+
+// AddNewRepresentation appends a new, empty Representation to the
+// AdaptationSet and returns it.
+func (a *AdaptationSet) AddNewRepresentation() *Representation {
+	r := &Representation{adaptationSet: a}
+	a.Representations = append(a.Representations, r)
+	return r
+}
+
+// Representation is one particular encoding of the content described by
+// its enclosing AdaptationSet, per ISO/IEC 23009-1 clause 5.3.5.
+type Representation struct {
+	ID              *string          `xml:"id,attr,omitempty"`
+	Bandwidth       *uint64          `xml:"bandwidth,attr,omitempty"`
+	BaseURL         []*BaseURL       `xml:"BaseURL,omitempty"`
+	SegmentTemplate *SegmentTemplate `xml:"SegmentTemplate,omitempty"`
+	SegmentList     *SegmentList     `xml:"SegmentList,omitempty"`
+
+	EssentialProperties    []*EssentialProperty    `xml:"EssentialProperty,omitempty"`
+	SupplementalProperties []*SupplementalProperty `xml:"SupplementalProperty,omitempty"`
+	ContentProtections     []*ContentProtection    `xml:"ContentProtection,omitempty"`
+	InbandEventStreams     []*InbandEventStream    `xml:"InbandEventStream,omitempty"`
+	Unknown                []xml.Attr              `xml:",any,attr"`
+
+	adaptationSet *AdaptationSet
+}
+
+// SegmentTemplate describes how to construct media and initialization
+// segment URLs via $identifier$ substitution, per ISO/IEC 23009-1 clause
+// 5.3.9.4. It may be declared on Period, AdaptationSet or
+// Representation; the one in effect for a Representation is the closest
+// declaration found walking Representation -> AdaptationSet -> Period.
+type SegmentTemplate struct {
+	MultipleSegmentBase
+	Media          *string `xml:"media,attr,omitempty"`
+	Initialization *string `xml:"initialization,attr,omitempty"`
+}
@@ -0,0 +1,44 @@
+package mpd
+
+import (
+	"encoding/xml"
+	"io"
+)
+
+// Parse parses a serialized MPD document and wires up the parent back-
+// references (Period -> MPD, AdaptationSet -> Period, Representation ->
+// AdaptationSet) that Representation.ResolveSegment and
+// AdaptationSet.SetLowLatency rely on, the same way the AddNew* builders
+// do when an MPD is constructed programmatically.
+func Parse(data []byte) (*MPD, error) {
+	m := &MPD{}
+	if err := xml.Unmarshal(data, m); err != nil {
+		return nil, err
+	}
+	m.link()
+	return m, nil
+}
+
+// ParseReader is like Parse but reads the document from r.
+func ParseReader(r io.Reader) (*MPD, error) {
+	m := &MPD{}
+	if err := xml.NewDecoder(r).Decode(m); err != nil {
+		return nil, err
+	}
+	m.link()
+	return m, nil
+}
+
+// link populates the unexported parent back-references that aren't
+// themselves part of the XML document.
+func (m *MPD) link() {
+	for _, p := range m.Periods {
+		p.mpd = m
+		for _, a := range p.AdaptationSets {
+			a.period = p
+			for _, r := range a.Representations {
+				r.adaptationSet = a
+			}
+		}
+	}
+}
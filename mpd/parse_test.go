@@ -0,0 +1,119 @@
+package mpd
+
+import (
+	"bytes"
+	"encoding/xml"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestParseRoundTrip exercises Parse against a small corpus of VOD,
+// live, low-latency and multi-DRM manifests and checks that
+// re-marshaling the parsed MPD reproduces the original bytes exactly.
+// The fixtures under testdata/ are synthetic: they were generated by
+// this package's own builders and Marshal rather than collected from
+// real services, so a byte-identical round trip here guarantees
+// Parse/Marshal agree with each other, not that they agree with every
+// manifest shape a real packager emits (attribute ordering from an
+// encoder that doesn't declare Unknown last, for instance, isn't
+// covered). TestParseRealisticManifests in testdata_realistic_test.go
+// covers that gap with a separate, hand-authored corpus. Between them
+// the fixtures touch, among others, Role, Accessibility,
+// EssentialProperty, SupplementalProperty, Label, EventStream/Event,
+// InbandEventStream, and ContentProtection's PSSH/PRO children; UTCTiming,
+// SegmentList/SegmentURL and SegmentBase's Initialization/
+// RepresentationIndex children don't appear in any fixture.
+// DefaultKID isn't exercised here: it's a namespaced
+// *attribute*, and re-marshaling one collides with this package's
+// Unknown []xml.Attr catch-all (the synthesized xmlns declaration for
+// the attribute gets captured by the catch-all on unmarshal and then
+// duplicated on marshal), so a round trip through this test would
+// legitimately fail on a pre-existing issue unrelated to what's being
+// tested here. TestUnmarshalContentProtectionDefaultKID below covers it
+// with a plain Unmarshal instead.
+func TestParseRoundTrip(t *testing.T) {
+	files, err := filepath.Glob("testdata/*.mpd")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) == 0 {
+		t.Fatal("no testdata/*.mpd fixtures found")
+	}
+
+	for _, file := range files {
+		file := file
+		t.Run(filepath.Base(file), func(t *testing.T) {
+			want, err := os.ReadFile(file)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			m, err := Parse(want)
+			if err != nil {
+				t.Fatalf("Parse: %v", err)
+			}
+
+			got, err := marshalMPD(m)
+			if err != nil {
+				t.Fatalf("marshalMPD: %v", err)
+			}
+
+			if !bytes.Equal(got, want) {
+				t.Errorf("round trip mismatch for %s:\n--- want ---\n%s\n--- got ---\n%s", file, want, got)
+			}
+		})
+	}
+}
+
+// TestUnmarshalContentProtectionDefaultKID covers the cenc:default_KID
+// attribute via a plain Unmarshal. See the comment on TestParseRoundTrip
+// for why it's not part of that round-trip corpus instead.
+func TestUnmarshalContentProtectionDefaultKID(t *testing.T) {
+	data := []byte(`<ContentProtection schemeIdUri="urn:mpeg:dash:mp4protection:2011" value="cenc" xmlns:cenc="urn:mpeg:cenc:2013" cenc:default_KID="34e5db32-8625-47cd-ba06-68fca5a2a77a"></ContentProtection>`)
+	var cp ContentProtection
+	if err := xml.Unmarshal(data, &cp); err != nil {
+		t.Fatal(err)
+	}
+	const want = "34e5db32-8625-47cd-ba06-68fca5a2a77a"
+	if cp.DefaultKID == nil || *cp.DefaultKID != want {
+		t.Errorf("DefaultKID = %v, want %q", cp.DefaultKID, want)
+	}
+}
+
+func TestParseReader(t *testing.T) {
+	want, err := os.ReadFile("testdata/vod.mpd")
+	if err != nil {
+		t.Fatal(err)
+	}
+	m, err := ParseReader(bytes.NewReader(want))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(m.Periods) != 1 || len(m.Periods[0].AdaptationSets) != 1 {
+		t.Fatalf("unexpected structure: %+v", m)
+	}
+}
+
+// TestParseLinksParents checks that Parse wires up the same parent
+// back-references the AddNew* builders set, so a parsed MPD can still
+// resolve segments and be extended with SetLowLatency.
+func TestParseLinksParents(t *testing.T) {
+	data, err := os.ReadFile("testdata/live.mpd")
+	if err != nil {
+		t.Fatal(err)
+	}
+	m, err := Parse(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := m.Periods[0].AdaptationSets[0].Representations[0]
+	_, mediaURL, err := r.ResolveSegment(2)
+	if err != nil {
+		t.Fatalf("ResolveSegment after Parse: %v", err)
+	}
+	const want = "/video-1/2000.m4s"
+	if mediaURL != want {
+		t.Errorf("mediaURL = %q, want %q", mediaURL, want)
+	}
+}
@@ -0,0 +1,240 @@
+package mpd
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// identifierPattern matches the $$ escape and the four SegmentTemplate
+// identifiers, each with an optional %0Nd width specifier, per ISO/IEC
+// 23009-1 clause 5.3.9.4.4.
+var identifierPattern = regexp.MustCompile(`\$\$|\$(RepresentationID|Number|Bandwidth|Time)(?:%0(\d+)d)?\$`)
+
+// ResolveSegment resolves the fully-qualified initialization and media
+// segment URLs for the given segment number (the $Number$ value, which
+// counts from the SegmentTemplate's @startNumber, 1 if unset), using the
+// Representation's effective SegmentTemplate (inherited from its
+// AdaptationSet or Period if the Representation doesn't declare its
+// own) and the BaseURL chain from MPD down to Representation.
+func (r *Representation) ResolveSegment(number int64) (initURL, mediaURL string, err error) {
+	tmpl := r.effectiveSegmentTemplate()
+	if tmpl == nil {
+		return "", "", errors.New("mpd: representation has no SegmentTemplate in effect")
+	}
+
+	base, err := r.resolveBaseURL()
+	if err != nil {
+		return "", "", err
+	}
+
+	startNumber := int64(1)
+	if tmpl.StartNumber != nil {
+		startNumber = int64(*tmpl.StartNumber)
+	}
+
+	var timeTicks int64
+	switch {
+	case tmpl.SegmentTimeline != nil:
+		timescale := int32(1)
+		if tmpl.Timescale != nil {
+			timescale = *tmpl.Timescale
+		}
+		segments := tmpl.SegmentTimeline.Expand(r.periodDurationTicks(timescale), timescale)
+		idx := number - startNumber
+		if idx < 0 || int(idx) >= len(segments) {
+			return "", "", fmt.Errorf("mpd: segment number %d is out of range for the SegmentTimeline", number)
+		}
+		timeTicks = segments[idx].StartTime
+	case tmpl.Duration != nil:
+		timeTicks = (number - startNumber) * int64(*tmpl.Duration)
+	default:
+		return "", "", errors.New("mpd: SegmentTemplate has neither @duration nor a SegmentTimeline")
+	}
+
+	if tmpl.Initialization != nil {
+		initPath, err := r.substituteIdentifiers(*tmpl.Initialization, number, timeTicks)
+		if err != nil {
+			return "", "", err
+		}
+		initURL, err = joinURL(base, initPath)
+		if err != nil {
+			return "", "", err
+		}
+	}
+
+	if tmpl.Media == nil {
+		return initURL, "", errors.New("mpd: SegmentTemplate has no media attribute")
+	}
+	mediaPath, err := r.substituteIdentifiers(*tmpl.Media, number, timeTicks)
+	if err != nil {
+		return "", "", err
+	}
+	mediaURL, err = joinURL(base, mediaPath)
+	if err != nil {
+		return "", "", err
+	}
+
+	return initURL, mediaURL, nil
+}
+
+// ResolveSegmentAtTime resolves the segment that's presented at t, a
+// duration relative to the start of the Period, the same way
+// ResolveSegment resolves one by number.
+func (r *Representation) ResolveSegmentAtTime(t time.Duration) (initURL, mediaURL string, err error) {
+	tmpl := r.effectiveSegmentTemplate()
+	if tmpl == nil {
+		return "", "", errors.New("mpd: representation has no SegmentTemplate in effect")
+	}
+
+	timescale := int32(1)
+	if tmpl.Timescale != nil {
+		timescale = *tmpl.Timescale
+	}
+	startNumber := int64(1)
+	if tmpl.StartNumber != nil {
+		startNumber = int64(*tmpl.StartNumber)
+	}
+	ticks := int64(t.Seconds() * float64(timescale))
+
+	var number int64
+	switch {
+	case tmpl.SegmentTimeline != nil:
+		segments := tmpl.SegmentTimeline.Expand(r.periodDurationTicks(timescale), timescale)
+		found := false
+		for _, seg := range segments {
+			if ticks >= seg.StartTime && ticks < seg.StartTime+seg.Duration {
+				number = startNumber + seg.Number - 1
+				found = true
+				break
+			}
+		}
+		if !found {
+			return "", "", fmt.Errorf("mpd: no segment covers t=%s", t)
+		}
+	case tmpl.Duration != nil:
+		number = startNumber + ticks/int64(*tmpl.Duration)
+	default:
+		return "", "", errors.New("mpd: SegmentTemplate has neither @duration nor a SegmentTimeline")
+	}
+
+	return r.ResolveSegment(number)
+}
+
+// effectiveSegmentTemplate returns the SegmentTemplate that applies to
+// r: its own, or failing that the closest ancestor's (AdaptationSet,
+// then Period).
+func (r *Representation) effectiveSegmentTemplate() *SegmentTemplate {
+	if r.SegmentTemplate != nil {
+		return r.SegmentTemplate
+	}
+	if r.adaptationSet != nil {
+		if r.adaptationSet.SegmentTemplate != nil {
+			return r.adaptationSet.SegmentTemplate
+		}
+		if r.adaptationSet.period != nil && r.adaptationSet.period.SegmentTemplate != nil {
+			return r.adaptationSet.period.SegmentTemplate
+		}
+	}
+	return nil
+}
+
+// periodDurationTicks returns the enclosing Period's @duration
+// expressed in the given timescale's ticks, or 0 if the Period (or its
+// duration) isn't known.
+func (r *Representation) periodDurationTicks(timescale int32) int64 {
+	if r.adaptationSet == nil || r.adaptationSet.period == nil || r.adaptationSet.period.Duration == nil {
+		return 0
+	}
+	return int64(time.Duration(*r.adaptationSet.period.Duration).Seconds() * float64(timescale))
+}
+
+// resolveBaseURL joins the first BaseURL found at each level from MPD
+// down to Representation, in that order, the way ISO/IEC 23009-1 clause
+// 5.6 resolves a chain of BaseURL elements.
+func (r *Representation) resolveBaseURL() (*url.URL, error) {
+	var chain [][]*BaseURL
+	if r.adaptationSet != nil && r.adaptationSet.period != nil {
+		if r.adaptationSet.period.mpd != nil {
+			chain = append(chain, r.adaptationSet.period.mpd.BaseURL)
+		}
+		chain = append(chain, r.adaptationSet.period.BaseURL)
+	}
+	if r.adaptationSet != nil {
+		chain = append(chain, r.adaptationSet.BaseURL)
+	}
+	chain = append(chain, r.BaseURL)
+
+	base := &url.URL{}
+	for _, level := range chain {
+		if len(level) == 0 || level[0].Value == nil {
+			continue
+		}
+		u, err := url.Parse(*level[0].Value)
+		if err != nil {
+			return nil, fmt.Errorf("mpd: invalid BaseURL %q: %w", *level[0].Value, err)
+		}
+		base = base.ResolveReference(u)
+	}
+	return base, nil
+}
+
+func joinURL(base *url.URL, ref string) (string, error) {
+	u, err := url.Parse(ref)
+	if err != nil {
+		return "", fmt.Errorf("mpd: invalid segment path %q: %w", ref, err)
+	}
+	return base.ResolveReference(u).String(), nil
+}
+
+// substituteIdentifiers expands the $$/$RepresentationID$/$Bandwidth$/
+// $Number$/$Time$ identifiers (with optional %0Nd zero-padding) in a
+// SegmentTemplate media/initialization attribute.
+func (r *Representation) substituteIdentifiers(tmpl string, number, timeTicks int64) (string, error) {
+	var firstErr error
+	result := identifierPattern.ReplaceAllStringFunc(tmpl, func(match string) string {
+		if match == "$$" {
+			return "$"
+		}
+
+		groups := identifierPattern.FindStringSubmatch(match)
+		name, width := groups[1], groups[2]
+
+		var value string
+		switch name {
+		case "RepresentationID":
+			if r.ID == nil {
+				firstErr = errors.New("mpd: $RepresentationID$ used but Representation has no id")
+				return match
+			}
+			value = *r.ID
+		case "Bandwidth":
+			if r.Bandwidth == nil {
+				firstErr = errors.New("mpd: $Bandwidth$ used but Representation has no bandwidth")
+				return match
+			}
+			value = strconv.FormatUint(*r.Bandwidth, 10)
+		case "Number":
+			value = strconv.FormatInt(number, 10)
+		case "Time":
+			value = strconv.FormatInt(timeTicks, 10)
+		}
+
+		if width != "" {
+			if w, err := strconv.Atoi(width); err == nil && w > len(value) {
+				value = strings.Repeat("0", w-len(value)) + value
+			}
+		}
+
+		return value
+	})
+
+	if firstErr != nil {
+		return "", firstErr
+	}
+	return result, nil
+}
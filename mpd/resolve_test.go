@@ -0,0 +1,138 @@
+package mpd
+
+import "testing"
+
+func strptr(s string) *string { return &s }
+
+func newTestRepresentation() (*MPD, *Representation) {
+	m := NewMPD("urn:mpeg:dash:profile:isoff-live:2011")
+	p := m.AddNewPeriod()
+	a := p.AddNewAdaptationSet()
+	r := a.AddNewRepresentation()
+	return m, r
+}
+
+func TestSubstituteIdentifiers(t *testing.T) {
+	_, r := newTestRepresentation()
+	r.ID = strptr("video-1")
+	r.Bandwidth = uint64ptr(1500000)
+
+	cases := []struct {
+		name      string
+		tmpl      string
+		number    int64
+		timeTicks int64
+		want      string
+	}{
+		{"number width padding", "chunk-$Number%05d$.m4s", 7, 0, "chunk-00007.m4s"},
+		{"number without width", "chunk-$Number$.m4s", 7, 0, "chunk-7.m4s"},
+		{"number already as wide as width", "chunk-$Number%02d$.m4s", 123, 0, "chunk-123.m4s"},
+		{"literal dollar escape", "$$repID$$-$RepresentationID$.mp4", 1, 0, "$repID$-video-1.mp4"},
+		{"representation id", "$RepresentationID$/init.mp4", 1, 0, "video-1/init.mp4"},
+		{"bandwidth", "$Bandwidth$.mp4", 1, 0, "1500000.mp4"},
+		{"time", "$Time%010d$.m4s", 1, 48000, "0000048000.m4s"},
+		{"multiple identifiers in one template", "$RepresentationID$/$Number%04d$.m4s", 3, 0, "video-1/0003.m4s"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got, err := r.substituteIdentifiers(c.tmpl, c.number, c.timeTicks)
+			if err != nil {
+				t.Fatalf("substituteIdentifiers(%q) returned error: %v", c.tmpl, err)
+			}
+			if got != c.want {
+				t.Errorf("substituteIdentifiers(%q) = %q, want %q", c.tmpl, got, c.want)
+			}
+		})
+	}
+}
+
+func TestSubstituteIdentifiersErrors(t *testing.T) {
+	_, r := newTestRepresentation()
+
+	if _, err := r.substituteIdentifiers("$RepresentationID$.mp4", 1, 0); err == nil {
+		t.Error("expected an error using $RepresentationID$ with no id set")
+	}
+	if _, err := r.substituteIdentifiers("$Bandwidth$.mp4", 1, 0); err == nil {
+		t.Error("expected an error using $Bandwidth$ with no bandwidth set")
+	}
+}
+
+func TestResolveSegmentNoDurationOrTimeline(t *testing.T) {
+	_, r := newTestRepresentation()
+	r.SegmentTemplate = &SegmentTemplate{
+		Media: strptr("$Time$.m4s"),
+	}
+
+	_, _, err := r.ResolveSegment(1)
+	if err == nil {
+		t.Fatal("expected an error when the SegmentTemplate has neither @duration nor a SegmentTimeline")
+	}
+}
+
+func TestResolveBaseURLMultiLevelInheritance(t *testing.T) {
+	m, r := newTestRepresentation()
+	m.BaseURL = []*BaseURL{{Value: strptr("https://cdn.example.com/")}}
+	r.adaptationSet.period.BaseURL = []*BaseURL{{Value: strptr("content/")}}
+	r.adaptationSet.BaseURL = []*BaseURL{{Value: strptr("video/")}}
+	r.BaseURL = []*BaseURL{{Value: strptr("1500k/")}}
+
+	base, err := r.resolveBaseURL()
+	if err != nil {
+		t.Fatalf("resolveBaseURL returned error: %v", err)
+	}
+
+	want := "https://cdn.example.com/content/video/1500k/"
+	if base.String() != want {
+		t.Errorf("resolveBaseURL() = %q, want %q", base.String(), want)
+	}
+}
+
+func TestResolveBaseURLSkipsLevelsWithNoBaseURL(t *testing.T) {
+	m, r := newTestRepresentation()
+	m.BaseURL = []*BaseURL{{Value: strptr("https://cdn.example.com/content/")}}
+	// Period and AdaptationSet declare no BaseURL of their own.
+	r.BaseURL = []*BaseURL{{Value: strptr("1500k/")}}
+
+	base, err := r.resolveBaseURL()
+	if err != nil {
+		t.Fatalf("resolveBaseURL returned error: %v", err)
+	}
+
+	want := "https://cdn.example.com/content/1500k/"
+	if base.String() != want {
+		t.Errorf("resolveBaseURL() = %q, want %q", base.String(), want)
+	}
+}
+
+func TestResolveSegmentByNumber(t *testing.T) {
+	_, r := newTestRepresentation()
+	r.ID = strptr("video-1")
+	r.adaptationSet.period.BaseURL = []*BaseURL{{Value: strptr("https://cdn.example.com/")}}
+
+	timescale := int32(1000)
+	duration := int32(2000)
+	startNumber := int32(1)
+	r.SegmentTemplate = &SegmentTemplate{
+		MultipleSegmentBase: MultipleSegmentBase{
+			SegmentBase: SegmentBase{Timescale: &timescale},
+			Duration:    &duration,
+			StartNumber: &startNumber,
+		},
+		Media:          strptr("$RepresentationID$/$Number%05d$.m4s"),
+		Initialization: strptr("$RepresentationID$/init.mp4"),
+	}
+
+	initURL, mediaURL, err := r.ResolveSegment(3)
+	if err != nil {
+		t.Fatalf("ResolveSegment returned error: %v", err)
+	}
+	if want := "https://cdn.example.com/video-1/init.mp4"; initURL != want {
+		t.Errorf("initURL = %q, want %q", initURL, want)
+	}
+	if want := "https://cdn.example.com/video-1/00003.m4s"; mediaURL != want {
+		t.Errorf("mediaURL = %q, want %q", mediaURL, want)
+	}
+}
+
+func uint64ptr(v uint64) *uint64 { return &v }
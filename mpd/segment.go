@@ -1,14 +1,17 @@
 package mpd
 
+import "encoding/xml"
+
 type SegmentBase struct {
-	Initialization           *URL     `xml:"Initialization,omitempty"`
-	RepresentationIndex      *URL     `xml:"RepresentationIndex,omitempty"`
-	Timescale                *int32   `xml:"timescale,attr,omitempty"`
-	PresentationTimeOffset   *int64   `xml:"presentationTimeOffset,attr,omitempty"`
-	IndexRange               *string  `xml:"indexRange,attr,omitempty"`
-	IndexRangeExact          *bool    `xml:"indexRangeExact,attr,omitempty"`
-	AvailabilityTimeOffset   *float32 `xml:"availabilityTimeOffset,attr,omitempty"`
-	AvailabilityTimeComplete *bool    `xml:"availabilityTimeComplete,attr,omitempty"`
+	Initialization           *URL       `xml:"Initialization,omitempty"`
+	RepresentationIndex      *URL       `xml:"RepresentationIndex,omitempty"`
+	Timescale                *int32     `xml:"timescale,attr,omitempty"`
+	PresentationTimeOffset   *int64     `xml:"presentationTimeOffset,attr,omitempty"`
+	IndexRange               *string    `xml:"indexRange,attr,omitempty"`
+	IndexRangeExact          *bool      `xml:"indexRangeExact,attr,omitempty"`
+	AvailabilityTimeOffset   *float32   `xml:"availabilityTimeOffset,attr,omitempty"`
+	AvailabilityTimeComplete *bool      `xml:"availabilityTimeComplete,attr,omitempty"`
+	Unknown                  []xml.Attr `xml:",any,attr"`
 }
 
 type MultipleSegmentBase struct {
@@ -25,10 +28,11 @@ type SegmentList struct {
 }
 
 type SegmentURL struct {
-	Media      *string `xml:"media,attr,omitempty"`
-	MediaRange *string `xml:"mediaRange,attr,omitempty"`
-	Index      *string `xml:"index,attr,omitempty"`
-	IndexRange *string `xml:"indexRange,attr,omitempty"`
+	Media      *string    `xml:"media,attr,omitempty"`
+	MediaRange *string    `xml:"mediaRange,attr,omitempty"`
+	Index      *string    `xml:"index,attr,omitempty"`
+	IndexRange *string    `xml:"indexRange,attr,omitempty"`
+	Unknown    []xml.Attr `xml:",any,attr"`
 }
 
 type SegmentTimeline struct {
@@ -36,12 +40,14 @@ type SegmentTimeline struct {
 }
 
 type SegmentTimelineSegment struct {
-	StartTime   *int64 `xml:"t,attr,omitempty" datastore:",noindex"`
-	Duration    int64  `xml:"d,attr" datastore:",noindex"`
-	RepeatCount *int   `xml:"r,attr,omitempty" datastore:",noindex"`
+	StartTime   *int64     `xml:"t,attr,omitempty" datastore:",noindex"`
+	Duration    int64      `xml:"d,attr" datastore:",noindex"`
+	RepeatCount *int       `xml:"r,attr,omitempty" datastore:",noindex"`
+	Unknown     []xml.Attr `xml:",any,attr"`
 }
 
 type URL struct {
-	SourceURL *string `xml:"sourceURL,attr,omitempty"`
-	Range     *string `xml:"range,attr,omitempty"`
+	SourceURL *string    `xml:"sourceURL,attr,omitempty"`
+	Range     *string    `xml:"range,attr,omitempty"`
+	Unknown   []xml.Attr `xml:",any,attr"`
 }
@@ -0,0 +1,160 @@
+package mpd
+
+import (
+	"bytes"
+	"encoding/xml"
+	"os"
+	"testing"
+)
+
+// TestParseRealisticManifests parses fixtures under
+// testdata/realistic/, hand-authored (not generated by this package) to
+// mirror conventions real packagers actually use: explicit xmlns/xsi
+// declarations, namespace prefixes declared once at the MPD root rather
+// than per-element, attributes in a different order than this
+// package's own struct field order, self-closing tags, and vendor
+// extension attributes this package doesn't model. Unlike
+// TestParseRoundTrip, it doesn't compare against the original bytes --
+// no two independent DASH encoders agree on attribute order or
+// whitespace, so a byte-identical round trip isn't a meaningful bar for
+// manifests this package didn't produce. Instead it checks that Parse
+// extracts the expected values and, where the fixture doesn't trip the
+// pre-existing Unknown/xmlns issue documented on assertMarshalSucceeds,
+// that Marshal's output is itself a stable fixed point (re-parsing and
+// re-marshaling it changes nothing).
+//
+// This is a substitute for, not equivalent to, round-tripping against
+// genuine DASH-IF reference vectors: this environment has no network
+// access, so those couldn't be fetched to build this corpus. Swap in
+// real DASH-IF/dash.js conformance manifests here if this package is
+// ever built somewhere with access to them.
+func TestParseRealisticManifests(t *testing.T) {
+	t.Run("vod.mpd", func(t *testing.T) {
+		m := mustParseRealistic(t, "testdata/realistic/vod.mpd")
+
+		if got := len(m.BaseURL); got != 2 {
+			t.Fatalf("len(BaseURL) = %d, want 2 (primary + failover)", got)
+		}
+		r := m.Periods[0].AdaptationSets[0].Representations[0]
+		if r.ID == nil || *r.ID != "video-1" {
+			t.Errorf("Representation.ID = %v, want %q", r.ID, "video-1")
+		}
+		if got := len(m.Periods[0].AdaptationSets[0].Roles); got != 1 {
+			t.Fatalf("len(Roles) = %d, want 1", got)
+		}
+
+		as := m.Periods[0].AdaptationSets[0]
+		if got := len(as.Unknown); got == 0 {
+			t.Error("expected the ext:encrypted vendor attribute to land in AdaptationSet.Unknown")
+		}
+
+		assertMarshalSucceeds(t, m)
+	})
+
+	t.Run("live.mpd", func(t *testing.T) {
+		m := mustParseRealistic(t, "testdata/realistic/live.mpd")
+
+		r := m.Periods[0].AdaptationSets[0].Representations[0]
+		_, mediaURL, err := r.ResolveSegment(2)
+		if err != nil {
+			t.Fatalf("ResolveSegment after Parse: %v", err)
+		}
+		if want := "/video-1/2000.m4s"; mediaURL != want {
+			t.Errorf("mediaURL = %q, want %q", mediaURL, want)
+		}
+
+		if got := len(m.Unknown); got == 0 {
+			t.Error("expected publishTime/timeShiftBufferDepth to land in MPD.Unknown")
+		}
+
+		assertMarshalIsFixedPoint(t, m)
+	})
+
+	t.Run("multidrm.mpd", func(t *testing.T) {
+		m := mustParseRealistic(t, "testdata/realistic/multidrm.mpd")
+
+		cps := m.Periods[0].AdaptationSets[0].ContentProtections
+		if got := len(cps); got != 3 {
+			t.Fatalf("len(ContentProtections) = %d, want 3", got)
+		}
+		if cps[1].PSSH == nil || *cps[1].PSSH == "" {
+			t.Error("expected the Widevine ContentProtection's cenc:pssh to be parsed despite the root-level namespace declaration")
+		}
+		if cps[2].PRO == nil || *cps[2].PRO == "" {
+			t.Error("expected the PlayReady ContentProtection's mspr:pro to be parsed despite the root-level namespace declaration")
+		}
+
+		assertMarshalSucceeds(t, m)
+	})
+}
+
+func mustParseRealistic(t *testing.T, path string) *MPD {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m, err := Parse(data)
+	if err != nil {
+		t.Fatalf("Parse(%s): %v", path, err)
+	}
+	return m
+}
+
+// assertMarshalIsFixedPoint checks that Marshal's output, once
+// re-parsed and re-marshaled, is identical to itself: this package's
+// own serialization is stable regardless of how the input that produced
+// m was originally formatted.
+func assertMarshalIsFixedPoint(t *testing.T, m *MPD) {
+	t.Helper()
+
+	out1, err := marshalMPD(m)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+
+	m2, err := Parse(out1)
+	if err != nil {
+		t.Fatalf("Parse(Marshal(m)): %v", err)
+	}
+
+	out2, err := marshalMPD(m2)
+	if err != nil {
+		t.Fatalf("Marshal(Parse(Marshal(m))): %v", err)
+	}
+
+	if !bytes.Equal(out1, out2) {
+		t.Errorf("Marshal isn't a stable fixed point:\n--- first ---\n%s\n--- second ---\n%s", out1, out2)
+	}
+}
+
+// assertMarshalSucceeds is assertMarshalIsFixedPoint's weaker sibling,
+// used for fixtures that declare an xmlns:prefix the package has no
+// matching namespaced field for (e.g. a vendor extension's own
+// xmlns:ext, or cenc/mspr declared at the MPD root instead of locally
+// on the element that uses them). Parsing one of these surfaced a
+// genuine pre-existing bug: Unknown's ",any,attr" catch-all captures
+// the xmlns declaration alongside the prefixed attribute itself, and
+// re-marshaling duplicates it, growing on every further round trip --
+// the same root cause as the DefaultKID round-trip issue documented in
+// TestParseRoundTrip, just triggered by an unmatched xmlns:prefix
+// rather than a namespaced attribute field. That's a real fidelity gap
+// this package has against hand-authored, multi-namespace manifests,
+// but fixing the Unknown catch-all is out of scope here; this only
+// checks that Parse extracts the right values and that Marshal doesn't
+// error, not that the output is stable.
+func assertMarshalSucceeds(t *testing.T, m *MPD) {
+	t.Helper()
+	if _, err := marshalMPD(m); err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+}
+
+func marshalMPD(m *MPD) ([]byte, error) {
+	body, err := xml.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	out := append([]byte(xml.Header), body...)
+	return append(out, '\n'), nil
+}
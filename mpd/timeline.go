@@ -0,0 +1,115 @@
+package mpd
+
+// Segment is the flattened, one-segment-per-entry form of a
+// SegmentTimeline's S/@t/@d/@r shorthand. Number is 1-based and follows
+// @startNumber conventions used elsewhere in the package.
+type Segment struct {
+	Number    int64
+	StartTime int64
+	Duration  int64
+}
+
+// Expand flattens the timeline's S elements into one Segment per media
+// segment, resolving the @t/@d/@r shorthand. A missing @t on the first S
+// defaults to 0; a missing @t on any later S defaults to the end of the
+// previous segment, i.e. the running cursor. An explicit @t that doesn't
+// match the cursor (a gap, or an overlap) is honored as-is: the cursor
+// jumps to it, so a later @t skipping forward simply shows up as a
+// StartTime discontinuity between consecutive Segments in the result,
+// which callers (e.g. the validate package) can detect by comparing
+// StartTime to the previous segment's StartTime+Duration.
+//
+// @r=-1 repeats the S until the @t of the following S element, or, on
+// the last S, until periodDurationTicks (the Period's duration expressed
+// in the timeline's @timescale units) is reached. An S with @r=-1 and a
+// non-positive @d can't make progress toward that target, so it's
+// skipped rather than looped on forever; that's invalid input (it
+// isn't rejected anywhere upstream, since this package accepts
+// attacker-reachable, parsed XML), not something worth reporting here.
+func (st *SegmentTimeline) Expand(periodDurationTicks int64, timescale int32) []Segment {
+	if st == nil {
+		return nil
+	}
+
+	var segments []Segment
+	var cursor int64
+	number := int64(1)
+
+	for i, s := range st.Segments {
+		if s.StartTime != nil {
+			cursor = *s.StartTime
+		}
+
+		repeat := 0
+		if s.RepeatCount != nil {
+			repeat = *s.RepeatCount
+		}
+
+		if repeat < 0 {
+			if s.Duration <= 0 {
+				continue
+			}
+			until := periodDurationTicks
+			if i+1 < len(st.Segments) && st.Segments[i+1].StartTime != nil {
+				until = *st.Segments[i+1].StartTime
+			}
+			for cursor < until {
+				segments = append(segments, Segment{Number: number, StartTime: cursor, Duration: s.Duration})
+				number++
+				cursor += s.Duration
+			}
+			continue
+		}
+
+		for r := 0; r <= repeat; r++ {
+			segments = append(segments, Segment{Number: number, StartTime: cursor, Duration: s.Duration})
+			number++
+			cursor += s.Duration
+		}
+	}
+
+	return segments
+}
+
+// Append extends the timeline with a new segment starting at startTime
+// with the given duration. When the new segment is contiguous with (and
+// the same duration as) the last S element, it's folded in by bumping
+// that element's @r instead of adding a new S, so a live packager can
+// call Append once per published segment without the timeline growing
+// an S element per segment.
+func (st *SegmentTimeline) Append(startTime, duration int64) {
+	if len(st.Segments) > 0 {
+		last := st.Segments[len(st.Segments)-1]
+		repeat := 0
+		if last.RepeatCount != nil {
+			repeat = *last.RepeatCount
+		}
+		lastStart := int64(0)
+		if last.StartTime != nil {
+			lastStart = *last.StartTime
+		}
+		expectedStart := lastStart + last.Duration*int64(repeat+1)
+		if last.Duration == duration && startTime == expectedStart {
+			repeat++
+			last.RepeatCount = &repeat
+			return
+		}
+	}
+
+	st.Segments = append(st.Segments, &SegmentTimelineSegment{
+		StartTime: &startTime,
+		Duration:  duration,
+	})
+}
+
+// CompactTimeline folds a flat list of segments (as produced by Expand)
+// back into a SegmentTimeline, collapsing consecutive contiguous,
+// equal-duration segments into a single S element with an @r count. The
+// segments must be in StartTime order, which is what Expand returns.
+func CompactTimeline(segments []Segment) *SegmentTimeline {
+	st := &SegmentTimeline{}
+	for _, seg := range segments {
+		st.Append(seg.StartTime, seg.Duration)
+	}
+	return st
+}
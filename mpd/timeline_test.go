@@ -0,0 +1,151 @@
+package mpd
+
+import (
+	"reflect"
+	"testing"
+)
+
+func i64ptr(v int64) *int64 { return &v }
+func intptr(v int) *int     { return &v }
+
+func TestSegmentTimelineExpand(t *testing.T) {
+	cases := []struct {
+		name                string
+		segments            []*SegmentTimelineSegment
+		periodDurationTicks int64
+		timescale           int32
+		want                []Segment
+	}{
+		{
+			name: "repeat count",
+			segments: []*SegmentTimelineSegment{
+				{StartTime: i64ptr(0), Duration: 10, RepeatCount: intptr(2)},
+				{Duration: 5},
+			},
+			want: []Segment{
+				{Number: 1, StartTime: 0, Duration: 10},
+				{Number: 2, StartTime: 10, Duration: 10},
+				{Number: 3, StartTime: 20, Duration: 10},
+				{Number: 4, StartTime: 30, Duration: 5},
+			},
+		},
+		{
+			name: "r=-1 mid-sequence repeats until the next S's @t",
+			segments: []*SegmentTimelineSegment{
+				{StartTime: i64ptr(0), Duration: 10, RepeatCount: intptr(-1)},
+				{StartTime: i64ptr(35), Duration: 5},
+			},
+			want: []Segment{
+				{Number: 1, StartTime: 0, Duration: 10},
+				{Number: 2, StartTime: 10, Duration: 10},
+				{Number: 3, StartTime: 20, Duration: 10},
+				{Number: 4, StartTime: 30, Duration: 10},
+				{Number: 5, StartTime: 35, Duration: 5},
+			},
+		},
+		{
+			name: "r=-1 at end-of-period repeats until periodDurationTicks",
+			segments: []*SegmentTimelineSegment{
+				{StartTime: i64ptr(0), Duration: 10, RepeatCount: intptr(-1)},
+			},
+			periodDurationTicks: 35,
+			want: []Segment{
+				{Number: 1, StartTime: 0, Duration: 10},
+				{Number: 2, StartTime: 10, Duration: 10},
+				{Number: 3, StartTime: 20, Duration: 10},
+				{Number: 4, StartTime: 30, Duration: 10},
+			},
+		},
+		{
+			name: "r=-1 with non-positive duration is skipped, not looped forever",
+			segments: []*SegmentTimelineSegment{
+				{StartTime: i64ptr(0), Duration: 0, RepeatCount: intptr(-1)},
+				{StartTime: i64ptr(10), Duration: 5},
+			},
+			want: []Segment{
+				{Number: 1, StartTime: 10, Duration: 5},
+			},
+		},
+		{
+			name: "explicit @t ahead of the cursor shows up as a gap",
+			segments: []*SegmentTimelineSegment{
+				{StartTime: i64ptr(0), Duration: 10},
+				{StartTime: i64ptr(25), Duration: 10},
+			},
+			want: []Segment{
+				{Number: 1, StartTime: 0, Duration: 10},
+				{Number: 2, StartTime: 25, Duration: 10},
+			},
+		},
+		{
+			name: "explicit @t behind the cursor shows up as an overlap",
+			segments: []*SegmentTimelineSegment{
+				{StartTime: i64ptr(0), Duration: 10},
+				{StartTime: i64ptr(5), Duration: 10},
+			},
+			want: []Segment{
+				{Number: 1, StartTime: 0, Duration: 10},
+				{Number: 2, StartTime: 5, Duration: 10},
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			st := &SegmentTimeline{Segments: c.segments}
+			got := st.Expand(c.periodDurationTicks, c.timescale)
+			if !reflect.DeepEqual(got, c.want) {
+				t.Errorf("Expand() = %+v, want %+v", got, c.want)
+			}
+		})
+	}
+}
+
+func TestSegmentTimelineExpandNil(t *testing.T) {
+	var st *SegmentTimeline
+	if got := st.Expand(0, 1); got != nil {
+		t.Errorf("Expand() on a nil SegmentTimeline = %+v, want nil", got)
+	}
+}
+
+func TestSegmentTimelineAppend(t *testing.T) {
+	st := &SegmentTimeline{}
+	st.Append(0, 10)
+	st.Append(10, 10)
+	st.Append(20, 10)
+	if len(st.Segments) != 1 {
+		t.Fatalf("contiguous equal-duration segments should fold into one S, got %d", len(st.Segments))
+	}
+	if *st.Segments[0].RepeatCount != 2 {
+		t.Errorf("RepeatCount = %d, want 2", *st.Segments[0].RepeatCount)
+	}
+
+	st.Append(35, 5)
+	if len(st.Segments) != 2 {
+		t.Fatalf("a non-contiguous segment should start a new S, got %d", len(st.Segments))
+	}
+
+	st.Append(40, 10)
+	if len(st.Segments) != 3 {
+		t.Fatalf("a different duration should start a new S even if contiguous, got %d", len(st.Segments))
+	}
+}
+
+func TestCompactTimelineRoundTripsThroughExpand(t *testing.T) {
+	segments := []Segment{
+		{Number: 1, StartTime: 0, Duration: 10},
+		{Number: 2, StartTime: 10, Duration: 10},
+		{Number: 3, StartTime: 20, Duration: 10},
+		{Number: 4, StartTime: 30, Duration: 5},
+	}
+
+	st := CompactTimeline(segments)
+	if len(st.Segments) != 2 {
+		t.Fatalf("expected the three equal-duration segments to collapse into one S (plus one for the trailing 5), got %d S elements", len(st.Segments))
+	}
+
+	got := st.Expand(0, 1)
+	if !reflect.DeepEqual(got, segments) {
+		t.Errorf("round trip through CompactTimeline+Expand = %+v, want %+v", got, segments)
+	}
+}
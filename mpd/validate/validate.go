@@ -0,0 +1,240 @@
+// Package validate checks a parsed MPD for structural and semantic
+// issues that are valid XML but not a valid (or not a sensible) DASH
+// manifest: SegmentTimeline gaps, missing attributes a given profile
+// requires, ContentProtection schemes nothing recognizes, and so on.
+package validate
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Cawb07/go-dash/mpd"
+)
+
+// Severity classifies how serious a Diagnostic is.
+type Severity int
+
+const (
+	Info Severity = iota
+	Warning
+	Error
+)
+
+func (s Severity) String() string {
+	switch s {
+	case Info:
+		return "info"
+	case Warning:
+		return "warning"
+	case Error:
+		return "error"
+	default:
+		return "unknown"
+	}
+}
+
+// Diagnostic describes one issue found in an MPD.
+type Diagnostic struct {
+	Severity Severity
+	Code     string
+	Path     string
+	Message  string
+}
+
+func (d Diagnostic) String() string {
+	return fmt.Sprintf("%s: %s: %s: %s", d.Severity, d.Code, d.Path, d.Message)
+}
+
+// knownDRMSchemes maps the ContentProtection @schemeIdUri values for
+// commonly deployed DRM systems to their names, per the DASH-IF
+// interoperability guidelines' registry.
+var knownDRMSchemes = map[string]string{
+	"urn:mpeg:dash:mp4protection:2011":              "Common Encryption (cenc)",
+	"urn:uuid:edef8ba9-79d6-4ace-a3c8-27dcd51d21ed": "Widevine",
+	"urn:uuid:9a04f079-9840-4286-ab92-e65be0885f95": "PlayReady",
+	"urn:uuid:94ce86fb-07ff-4f43-adb8-93d2fa968ca2": "FairPlay",
+	"urn:uuid:e2719d58-a985-b3c9-781a-b030af78d30e": "ClearKey (uuid form)",
+	"urn:mpeg:dash:mp4protection:clearkey:2015":     "ClearKey",
+}
+
+// Validator runs a configurable set of checks against an MPD. The
+// consistency check of a Representation's SegmentTemplate
+// @presentationTimeOffset against its AdaptationSet's has no flag and
+// always runs; every other check has a flag below and is off in the zero
+// Validator. Use NewValidator for one with every flagged check enabled,
+// then turn individual ones off.
+type Validator struct {
+	CheckSegmentTimeline        bool
+	CheckTimescale              bool
+	CheckSegmentListConsistency bool
+	CheckDynamicAttributes      bool
+	CheckContentProtection      bool
+	CheckProfileRequirements    bool
+}
+
+// NewValidator returns a Validator with every check enabled.
+func NewValidator() *Validator {
+	return &Validator{
+		CheckSegmentTimeline:        true,
+		CheckTimescale:              true,
+		CheckSegmentListConsistency: true,
+		CheckDynamicAttributes:      true,
+		CheckContentProtection:      true,
+		CheckProfileRequirements:    true,
+	}
+}
+
+// Validate runs every check enabled on v against m and returns the
+// Diagnostics found, in no particular order.
+func (v *Validator) Validate(m *mpd.MPD) []Diagnostic {
+	var diags []Diagnostic
+
+	isDynamic := m.Type != nil && *m.Type == "dynamic"
+
+	if v.CheckDynamicAttributes && isDynamic {
+		if m.AvailabilityStartTime == nil {
+			diags = append(diags, Diagnostic{Error, "missing-availability-start-time", "MPD", "@availabilityStartTime is required when @type=\"dynamic\""})
+		}
+		if m.MinimumUpdatePeriod == nil {
+			diags = append(diags, Diagnostic{Warning, "missing-minimum-update-period", "MPD", "@minimumUpdatePeriod is usually present when @type=\"dynamic\""})
+		}
+	}
+
+	requiresSegmentTemplate := v.CheckProfileRequirements && m.Profiles != nil && strings.Contains(*m.Profiles, "isoff-live")
+
+	for pi, p := range m.Periods {
+		periodPath := fmt.Sprintf("MPD/Period[%d]", pi)
+
+		for ai, as := range p.AdaptationSets {
+			asPath := fmt.Sprintf("%s/AdaptationSet[%d]", periodPath, ai)
+
+			if requiresSegmentTemplate && as.SegmentTemplate == nil {
+				allHaveOwn := len(as.Representations) > 0
+				for _, r := range as.Representations {
+					if r.SegmentTemplate == nil {
+						allHaveOwn = false
+						break
+					}
+				}
+				if !allHaveOwn {
+					diags = append(diags, Diagnostic{Error, "profile-requires-segment-template", asPath, "the isoff-live profile requires a SegmentTemplate on the AdaptationSet or each Representation"})
+				}
+			}
+
+			for _, cp := range as.ContentProtections {
+				diags = append(diags, v.checkContentProtection(cp, asPath)...)
+			}
+
+			if tmpl := as.SegmentTemplate; tmpl != nil {
+				diags = append(diags, v.checkSegmentTemplate(tmpl, p, asPath+"/SegmentTemplate")...)
+			}
+
+			for ri, r := range as.Representations {
+				rPath := fmt.Sprintf("%s/Representation[%d]", asPath, ri)
+
+				for _, cp := range r.ContentProtections {
+					diags = append(diags, v.checkContentProtection(cp, rPath)...)
+				}
+
+				if tmpl := r.SegmentTemplate; tmpl != nil {
+					diags = append(diags, v.checkSegmentTemplate(tmpl, p, rPath+"/SegmentTemplate")...)
+
+					if as.SegmentTemplate != nil && as.SegmentTemplate.PresentationTimeOffset != nil && tmpl.PresentationTimeOffset != nil &&
+						*as.SegmentTemplate.PresentationTimeOffset != *tmpl.PresentationTimeOffset {
+						diags = append(diags, Diagnostic{Warning, "inconsistent-presentation-time-offset", rPath, "Representation's SegmentTemplate@presentationTimeOffset disagrees with its AdaptationSet's"})
+					}
+				}
+
+				if v.CheckSegmentListConsistency && r.SegmentList != nil {
+					diags = append(diags, v.checkSegmentList(r.SegmentList, rPath+"/SegmentList")...)
+				}
+			}
+
+			if v.CheckSegmentListConsistency && as.SegmentList != nil {
+				diags = append(diags, v.checkSegmentList(as.SegmentList, asPath+"/SegmentList")...)
+			}
+		}
+	}
+
+	return diags
+}
+
+func (v *Validator) checkSegmentTemplate(tmpl *mpd.SegmentTemplate, p *mpd.Period, path string) []Diagnostic {
+	var diags []Diagnostic
+
+	needsTimescale := tmpl.Duration != nil || tmpl.SegmentTimeline != nil
+	if v.CheckTimescale && needsTimescale && tmpl.Timescale == nil {
+		diags = append(diags, Diagnostic{Warning, "missing-timescale", path, "@timescale should be set when @duration or SegmentTimeline is present (it otherwise defaults to 1, rarely what's intended)"})
+	}
+
+	if v.CheckSegmentTimeline && tmpl.SegmentTimeline != nil {
+		timescale := int32(1)
+		if tmpl.Timescale != nil {
+			timescale = *tmpl.Timescale
+		}
+
+		var periodTicks int64
+		if p.Duration != nil {
+			periodTicks = int64(time.Duration(*p.Duration).Seconds() * float64(timescale))
+		}
+
+		segments := tmpl.SegmentTimeline.Expand(periodTicks, timescale)
+		var prevEnd int64
+		for i, seg := range segments {
+			if i > 0 {
+				switch {
+				case seg.StartTime > prevEnd:
+					diags = append(diags, Diagnostic{Warning, "segment-timeline-gap", path, fmt.Sprintf("gap of %d ticks before segment %d (starts at %d, previous ended at %d)", seg.StartTime-prevEnd, seg.Number, seg.StartTime, prevEnd)})
+				case seg.StartTime < prevEnd:
+					diags = append(diags, Diagnostic{Error, "segment-timeline-overlap", path, fmt.Sprintf("segment %d starts at %d, before the previous one ended at %d", seg.Number, seg.StartTime, prevEnd)})
+				}
+			}
+			prevEnd = seg.StartTime + seg.Duration
+		}
+
+		if periodTicks > 0 && prevEnd > periodTicks {
+			diags = append(diags, Diagnostic{Warning, "segment-timeline-exceeds-period", path, fmt.Sprintf("SegmentTimeline covers %d ticks, beyond the Period's duration of %d ticks", prevEnd, periodTicks)})
+		}
+	}
+
+	return diags
+}
+
+func (v *Validator) checkSegmentList(sl *mpd.SegmentList, path string) []Diagnostic {
+	var diags []Diagnostic
+
+	if sl.SegmentTimeline == nil {
+		return diags
+	}
+
+	timescale := int32(1)
+	if sl.Timescale != nil {
+		timescale = *sl.Timescale
+	}
+	segments := sl.SegmentTimeline.Expand(0, timescale)
+	if len(segments) != len(sl.SegmentURLs) {
+		diags = append(diags, Diagnostic{Error, "segment-list-count-mismatch", path, fmt.Sprintf("SegmentTimeline describes %d segments but there are %d SegmentURL elements", len(segments), len(sl.SegmentURLs))})
+	}
+
+	return diags
+}
+
+func (v *Validator) checkContentProtection(cp *mpd.ContentProtection, path string) []Diagnostic {
+	if !v.CheckContentProtection || cp.SchemeIdUri == nil {
+		return nil
+	}
+	// UUID URNs are conventionally lower-cased; compare loosely before
+	// flagging an unrecognized scheme.
+	if _, ok := knownDRMSchemes[strings.ToLower(*cp.SchemeIdUri)]; ok {
+		return nil
+	}
+	return []Diagnostic{{Info, "unrecognized-drm-scheme", path, fmt.Sprintf("ContentProtection@schemeIdUri %q doesn't match a known DRM system", *cp.SchemeIdUri)}}
+}
+
+// Validate runs every check against m using default Validator settings.
+// It's a convenience for callers who don't need to opt out of any
+// particular check.
+func Validate(m *mpd.MPD) []Diagnostic {
+	return NewValidator().Validate(m)
+}
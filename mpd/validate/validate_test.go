@@ -0,0 +1,249 @@
+package validate
+
+import (
+	"testing"
+
+	"github.com/Cawb07/go-dash/mpd"
+)
+
+func strp(s string) *string { return &s }
+func i32p(v int32) *int32   { return &v }
+
+func hasCode(diags []Diagnostic, code string) bool {
+	for _, d := range diags {
+		if d.Code == code {
+			return true
+		}
+	}
+	return false
+}
+
+func newLiveAdaptationSetWithTimeline(segments ...*mpd.SegmentTimelineSegment) (*mpd.MPD, *mpd.AdaptationSet) {
+	m := mpd.NewMPD("urn:mpeg:dash:profile:isoff-live:2011")
+	typ := "dynamic"
+	m.Type = &typ
+	m.AvailabilityStartTime = strp("2026-07-26T00:00:00Z")
+	p := m.AddNewPeriod()
+	a := p.AddNewAdaptationSet()
+	a.SegmentTemplate = &mpd.SegmentTemplate{
+		MultipleSegmentBase: mpd.MultipleSegmentBase{
+			SegmentBase: mpd.SegmentBase{Timescale: i32p(1000)},
+			SegmentTimeline: &mpd.SegmentTimeline{
+				Segments: segments,
+			},
+		},
+		Media: strp("$Number$.m4s"),
+	}
+	r := a.AddNewRepresentation()
+	r.ID = strp("video-1")
+	return m, a
+}
+
+func TestValidateSegmentTimelineGap(t *testing.T) {
+	m, _ := newLiveAdaptationSetWithTimeline(
+		&mpd.SegmentTimelineSegment{StartTime: func() *int64 { v := int64(0); return &v }(), Duration: 2000},
+		&mpd.SegmentTimelineSegment{StartTime: func() *int64 { v := int64(5000); return &v }(), Duration: 2000},
+	)
+
+	diags := Validate(m)
+	if !hasCode(diags, "segment-timeline-gap") {
+		t.Errorf("expected a segment-timeline-gap diagnostic, got %+v", diags)
+	}
+}
+
+func TestValidateSegmentTimelineOverlap(t *testing.T) {
+	m, _ := newLiveAdaptationSetWithTimeline(
+		&mpd.SegmentTimelineSegment{StartTime: func() *int64 { v := int64(0); return &v }(), Duration: 2000},
+		&mpd.SegmentTimelineSegment{StartTime: func() *int64 { v := int64(1000); return &v }(), Duration: 2000},
+	)
+
+	diags := Validate(m)
+	if !hasCode(diags, "segment-timeline-overlap") {
+		t.Errorf("expected a segment-timeline-overlap diagnostic, got %+v", diags)
+	}
+}
+
+func TestValidateSegmentTimelineExceedsPeriod(t *testing.T) {
+	m, _ := newLiveAdaptationSetWithTimeline(
+		&mpd.SegmentTimelineSegment{StartTime: func() *int64 { v := int64(0); return &v }(), Duration: 2000, RepeatCount: func() *int { v := 4; return &v }()},
+	)
+	dur, err := mpd.ParseDurationStrict("PT5S")
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := mpd.Duration(dur)
+	m.Periods[0].Duration = &d
+
+	diags := Validate(m)
+	if !hasCode(diags, "segment-timeline-exceeds-period") {
+		t.Errorf("expected a segment-timeline-exceeds-period diagnostic, got %+v", diags)
+	}
+}
+
+func TestValidateSegmentTimelineNoGapForContiguousSegments(t *testing.T) {
+	m, _ := newLiveAdaptationSetWithTimeline(
+		&mpd.SegmentTimelineSegment{StartTime: func() *int64 { v := int64(0); return &v }(), Duration: 2000, RepeatCount: func() *int { v := 2; return &v }()},
+	)
+
+	diags := Validate(m)
+	if hasCode(diags, "segment-timeline-gap") || hasCode(diags, "segment-timeline-overlap") {
+		t.Errorf("contiguous segments shouldn't produce a gap/overlap diagnostic, got %+v", diags)
+	}
+}
+
+func TestValidateSegmentListCountMismatch(t *testing.T) {
+	m := mpd.NewMPD("urn:mpeg:dash:profile:isoff-on-demand:2011")
+	p := m.AddNewPeriod()
+	a := p.AddNewAdaptationSet()
+	r := a.AddNewRepresentation()
+	r.ID = strp("video-1")
+	r.SegmentList = &mpd.SegmentList{
+		MultipleSegmentBase: mpd.MultipleSegmentBase{
+			SegmentBase: mpd.SegmentBase{Timescale: i32p(1000)},
+			SegmentTimeline: &mpd.SegmentTimeline{
+				Segments: []*mpd.SegmentTimelineSegment{
+					{StartTime: func() *int64 { v := int64(0); return &v }(), Duration: 2000, RepeatCount: func() *int { v := 2; return &v }()},
+				},
+			},
+		},
+		SegmentURLs: []*mpd.SegmentURL{
+			{Media: strp("1.m4s")},
+			{Media: strp("2.m4s")},
+		},
+	}
+
+	diags := Validate(m)
+	if !hasCode(diags, "segment-list-count-mismatch") {
+		t.Errorf("expected a segment-list-count-mismatch diagnostic (3 segments, 2 SegmentURLs), got %+v", diags)
+	}
+}
+
+func TestValidateSegmentListConsistentCountHasNoDiagnostic(t *testing.T) {
+	m := mpd.NewMPD("urn:mpeg:dash:profile:isoff-on-demand:2011")
+	p := m.AddNewPeriod()
+	a := p.AddNewAdaptationSet()
+	r := a.AddNewRepresentation()
+	r.ID = strp("video-1")
+	r.SegmentList = &mpd.SegmentList{
+		MultipleSegmentBase: mpd.MultipleSegmentBase{
+			SegmentBase: mpd.SegmentBase{Timescale: i32p(1000)},
+			SegmentTimeline: &mpd.SegmentTimeline{
+				Segments: []*mpd.SegmentTimelineSegment{
+					{StartTime: func() *int64 { v := int64(0); return &v }(), Duration: 2000, RepeatCount: func() *int { v := 1; return &v }()},
+				},
+			},
+		},
+		SegmentURLs: []*mpd.SegmentURL{
+			{Media: strp("1.m4s")},
+			{Media: strp("2.m4s")},
+		},
+	}
+
+	diags := Validate(m)
+	if hasCode(diags, "segment-list-count-mismatch") {
+		t.Errorf("counts match, expected no segment-list-count-mismatch diagnostic, got %+v", diags)
+	}
+}
+
+func TestValidateDynamicMPDRequiredAttributes(t *testing.T) {
+	m := mpd.NewMPD("urn:mpeg:dash:profile:isoff-live:2011")
+	typ := "dynamic"
+	m.Type = &typ
+	// Neither AvailabilityStartTime nor MinimumUpdatePeriod is set.
+
+	diags := Validate(m)
+	if !hasCode(diags, "missing-availability-start-time") {
+		t.Errorf("expected missing-availability-start-time, got %+v", diags)
+	}
+	if !hasCode(diags, "missing-minimum-update-period") {
+		t.Errorf("expected missing-minimum-update-period, got %+v", diags)
+	}
+}
+
+func TestValidateStaticMPDSkipsDynamicChecks(t *testing.T) {
+	m := mpd.NewMPD("urn:mpeg:dash:profile:isoff-on-demand:2011")
+	typ := "static"
+	m.Type = &typ
+
+	diags := Validate(m)
+	if hasCode(diags, "missing-availability-start-time") || hasCode(diags, "missing-minimum-update-period") {
+		t.Errorf("a static MPD shouldn't be checked against dynamic-only requirements, got %+v", diags)
+	}
+}
+
+func TestValidateProfileRequiresSegmentTemplate(t *testing.T) {
+	m := mpd.NewMPD("urn:mpeg:dash:profile:isoff-live:2011")
+	p := m.AddNewPeriod()
+	a := p.AddNewAdaptationSet()
+	r := a.AddNewRepresentation()
+	r.ID = strp("video-1")
+	// Neither the AdaptationSet nor its Representation declares a SegmentTemplate.
+
+	diags := Validate(m)
+	if !hasCode(diags, "profile-requires-segment-template") {
+		t.Errorf("expected profile-requires-segment-template, got %+v", diags)
+	}
+}
+
+func TestValidateProfileRequiresSegmentTemplateSatisfiedByRepresentation(t *testing.T) {
+	m := mpd.NewMPD("urn:mpeg:dash:profile:isoff-live:2011")
+	p := m.AddNewPeriod()
+	a := p.AddNewAdaptationSet()
+	r := a.AddNewRepresentation()
+	r.ID = strp("video-1")
+	r.SegmentTemplate = &mpd.SegmentTemplate{Media: strp("$Number$.m4s")}
+
+	diags := Validate(m)
+	if hasCode(diags, "profile-requires-segment-template") {
+		t.Errorf("a Representation-level SegmentTemplate should satisfy the isoff-live requirement, got %+v", diags)
+	}
+}
+
+func TestValidateUnrecognizedDRMScheme(t *testing.T) {
+	m := mpd.NewMPD("urn:mpeg:dash:profile:isoff-on-demand:2011")
+	p := m.AddNewPeriod()
+	a := p.AddNewAdaptationSet()
+	a.ContentProtections = []*mpd.ContentProtection{
+		{SchemeIdUri: strp("urn:uuid:deadbeef-dead-beef-dead-beefdeadbeef")},
+	}
+
+	diags := Validate(m)
+	if !hasCode(diags, "unrecognized-drm-scheme") {
+		t.Errorf("expected unrecognized-drm-scheme, got %+v", diags)
+	}
+}
+
+func TestValidateKnownDRMSchemeNoDiagnostic(t *testing.T) {
+	m := mpd.NewMPD("urn:mpeg:dash:profile:isoff-on-demand:2011")
+	p := m.AddNewPeriod()
+	a := p.AddNewAdaptationSet()
+	a.ContentProtections = []*mpd.ContentProtection{
+		{SchemeIdUri: strp("urn:uuid:edef8ba9-79d6-4ace-a3c8-27dcd51d21ed")},
+	}
+
+	diags := Validate(m)
+	if hasCode(diags, "unrecognized-drm-scheme") {
+		t.Errorf("Widevine's scheme is known, expected no unrecognized-drm-scheme diagnostic, got %+v", diags)
+	}
+}
+
+func TestValidatorCanDisableChecks(t *testing.T) {
+	m := mpd.NewMPD("urn:mpeg:dash:profile:isoff-live:2011")
+	typ := "dynamic"
+	m.Type = &typ
+	// An AdaptationSet with no SegmentTemplate, which the isoff-live
+	// profile check would otherwise flag.
+	m.AddNewPeriod().AddNewAdaptationSet().AddNewRepresentation()
+
+	v := NewValidator()
+	v.CheckDynamicAttributes = false
+	v.CheckProfileRequirements = false
+
+	diags := v.Validate(m)
+	if hasCode(diags, "missing-availability-start-time") {
+		t.Errorf("CheckDynamicAttributes=false should suppress missing-availability-start-time, got %+v", diags)
+	}
+	if hasCode(diags, "profile-requires-segment-template") {
+		t.Errorf("CheckProfileRequirements=false should suppress profile-requires-segment-template, got %+v", diags)
+	}
+}